@@ -0,0 +1,141 @@
+package compositor
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+// newCanvas 创建一块给定尺寸的画布；bg 为 nil 时画布保持透明
+func newCanvas(width, height int, bg color.Color) *image.RGBA {
+	if bg == nil {
+		return image.NewRGBA(image.Rect(0, 0, width, height))
+	}
+	return imaging.New(width, height, bg)
+}
+
+// ParseBackground 把画布背景色的原始输入解析为 color.Color。raw 为空字符串表示
+// 调用方未显式指定：支持透明通道的格式（PNG/PDF）保持透明背景，不支持透明通道的
+// 格式（JPEG/WebP）则默认使用不透明白色——否则透明区域会被这些编码器当作黑色写出。
+// 显式指定时，"transparent" 强制透明，"white"/"black" 是两个常用命名颜色，其余按
+// #RRGGBB 或 #RRGGBBAA 十六进制解析。
+func ParseBackground(raw string, supportsAlpha bool) (color.Color, error) {
+	if raw == "" {
+		if supportsAlpha {
+			return nil, nil
+		}
+		return color.White, nil
+	}
+
+	switch strings.ToLower(raw) {
+	case "transparent":
+		return nil, nil
+	case "white":
+		return color.White, nil
+	case "black":
+		return color.Black, nil
+	}
+
+	hex := strings.TrimPrefix(raw, "#")
+	var r, g, b uint8
+	a := uint8(255)
+	switch len(hex) {
+	case 6:
+		if _, err := fmt.Sscanf(hex, "%02x%02x%02x", &r, &g, &b); err != nil {
+			return nil, fmt.Errorf("无法解析背景色 %q: %w", raw, err)
+		}
+	case 8:
+		if _, err := fmt.Sscanf(hex, "%02x%02x%02x%02x", &r, &g, &b, &a); err != nil {
+			return nil, fmt.Errorf("无法解析背景色 %q: %w", raw, err)
+		}
+	default:
+		return nil, fmt.Errorf("无法解析背景色 %q，应为 white/black/transparent 或 #RRGGBB(AA)", raw)
+	}
+
+	return color.NRGBA{R: r, G: g, B: b, A: a}, nil
+}
+
+// scaled 按 scale 缩放一个尺寸，scale <= 0 时视为 1（不缩放）
+func scaled(n int, scale float64) int {
+	if scale <= 0 {
+		scale = 1
+	}
+	return int(float64(n) * scale)
+}
+
+// defaultShadowBlur 与 defaultShadowOffsetY 是 ShadowOptions 未显式设置对应字段时使用的默认值
+const (
+	defaultShadowBlur    = 20.0
+	defaultShadowOffsetY = 16
+)
+
+// defaultShadowColor 是未显式指定阴影颜色时使用的半透明黑色
+var defaultShadowColor = color.NRGBA{A: 110}
+
+// ShadowOptions 描述贴外壳时是否在其下方叠加一层柔和投影
+type ShadowOptions struct {
+	Enabled bool
+	OffsetX int
+	OffsetY int
+	Blur    float64     // 高斯模糊半径，<= 0 时使用 defaultShadowBlur
+	Color   color.NRGBA // 投影颜色（含 alpha），零值时使用 defaultShadowColor
+}
+
+// compositeShell 把设备外壳贴到画布的 rect 位置；若启用了阴影，先在外壳下方
+// 画一层由外壳 alpha 通道生成并做了高斯模糊的投影
+func compositeShell(canvas *image.RGBA, shell image.Image, rect image.Rectangle, shadow ShadowOptions) {
+	if shadow.Enabled {
+		drawShellShadow(canvas, shell, rect, shadow)
+	}
+
+	draw.Draw(canvas, rect, shell, shell.Bounds().Min, draw.Over)
+}
+
+// drawShellShadow 把外壳的 alpha 通道转成一张纯色蒙版，做高斯模糊后按偏移量叠加在外壳下方
+func drawShellShadow(canvas *image.RGBA, shell image.Image, rect image.Rectangle, shadow ShadowOptions) {
+	blurRadius := shadow.Blur
+	if blurRadius <= 0 {
+		blurRadius = defaultShadowBlur
+	}
+	shadowColor := shadow.Color
+	if shadowColor == (color.NRGBA{}) {
+		shadowColor = defaultShadowColor
+	}
+	offsetY := shadow.OffsetY
+	if offsetY == 0 {
+		offsetY = defaultShadowOffsetY
+	}
+
+	mask := shellAlphaMask(shell, shadowColor)
+	blurred := imaging.Blur(mask, blurRadius)
+
+	shadowRect := image.Rect(
+		rect.Min.X+shadow.OffsetX, rect.Min.Y+offsetY,
+		rect.Min.X+shadow.OffsetX+blurred.Bounds().Dx(), rect.Min.Y+offsetY+blurred.Bounds().Dy(),
+	)
+	draw.Draw(canvas, shadowRect, blurred, blurred.Bounds().Min, draw.Over)
+}
+
+// shellAlphaMask 生成一张与外壳同尺寸的纯色图片，每个像素的 alpha 继承自外壳原图，
+// 作为投影在高斯模糊前的输入
+func shellAlphaMask(shell image.Image, shadowColor color.NRGBA) *image.NRGBA {
+	bounds := shell.Bounds()
+	mask := image.NewNRGBA(bounds)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			_, _, _, a := shell.At(x, y).RGBA()
+			alpha := uint8(a >> 8)
+			mask.SetNRGBA(x, y, color.NRGBA{
+				R: shadowColor.R, G: shadowColor.G, B: shadowColor.B,
+				A: uint8(uint32(alpha) * uint32(shadowColor.A) / 255),
+			})
+		}
+	}
+
+	return mask
+}
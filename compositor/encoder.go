@@ -0,0 +1,80 @@
+package compositor
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// Encoder 将合成后的单张画布编码为具体的输出格式
+type Encoder interface {
+	// Name 返回格式名称，用于 --format 参数匹配
+	Name() string
+	// Extension 返回建议的文件扩展名（包含前导点）
+	Extension() string
+	// SupportsAlpha 返回该格式是否保留透明通道，用于在调用方未显式指定背景色时
+	// 选择合适的默认背景（不支持透明通道的格式需要一个不透明的默认背景）
+	SupportsAlpha() bool
+	// Encode 将画布写入 w
+	Encode(w io.Writer, canvas image.Image) error
+}
+
+// NamedImage 是多页输出（如 PDF）中的一页，Name 通常是设备名，用于内部标识/调试
+type NamedImage struct {
+	Name  string
+	Image image.Image
+}
+
+// MultiPageEncoder 是额外支持多页输出的编码器；Encode 仍然可用，表现为单页输出
+type MultiPageEncoder interface {
+	Encoder
+	// EncodePages 将每一页分别写成独立的一页（如 PDF 的每一页对应一个设备）
+	EncodePages(w io.Writer, pages []NamedImage) error
+}
+
+type pngEncoder struct{}
+
+// PNGEncoder 编码为 PNG，保留透明通道
+var PNGEncoder Encoder = pngEncoder{}
+
+func (pngEncoder) Name() string        { return "png" }
+func (pngEncoder) Extension() string   { return ".png" }
+func (pngEncoder) SupportsAlpha() bool { return true }
+func (pngEncoder) Encode(w io.Writer, canvas image.Image) error {
+	return png.Encode(w, canvas)
+}
+
+type jpegEncoder struct{ quality int }
+
+// NewJPEGEncoder 创建一个 JPEG 编码器，quality 取值 1-100，超出范围时回退为 90
+func NewJPEGEncoder(quality int) Encoder {
+	if quality <= 0 || quality > 100 {
+		quality = 90
+	}
+	return jpegEncoder{quality: quality}
+}
+
+func (e jpegEncoder) Name() string        { return "jpeg" }
+func (e jpegEncoder) Extension() string   { return ".jpg" }
+func (e jpegEncoder) SupportsAlpha() bool { return false }
+func (e jpegEncoder) Encode(w io.Writer, canvas image.Image) error {
+	return jpeg.Encode(w, canvas, &jpeg.Options{Quality: e.quality})
+}
+
+// LookupEncoder 按格式名称与（可选）质量参数构造一个编码器；quality 仅对 jpeg/webp 生效
+func LookupEncoder(format string, quality int) (Encoder, error) {
+	switch format {
+	case "", "png":
+		return PNGEncoder, nil
+	case "jpeg", "jpg":
+		return NewJPEGEncoder(quality), nil
+	case "webp":
+		return NewWebPEncoder(quality), nil
+	case "pdf":
+		return PDFEncoder, nil
+	default:
+		return nil, fmt.Errorf("未知的输出格式: %s", format)
+	}
+}
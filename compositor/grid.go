@@ -0,0 +1,93 @@
+package compositor
+
+import (
+	"image"
+
+	"github.com/disintegration/imaging"
+	"golang.org/x/image/draw"
+)
+
+// gridPadding 是网格/横向长条布局中设备之间以及画布边缘的间距
+const gridPadding = 60
+
+// gridColumns 是网格布局每行摆放的设备数量上限
+const gridColumns = 2
+
+type gridLayout struct{}
+
+// PresetGrid 将所有设备的外壳按网格摆放，行数随设备数量自适应
+var PresetGrid Layout = gridLayout{}
+
+func (gridLayout) Name() string { return "grid" }
+
+func (gridLayout) Compose(shots []DeviceShot, opts Options) (*image.RGBA, error) {
+	cols := gridColumns
+	if cols > len(shots) {
+		cols = len(shots)
+	}
+	return composeGrid(shots, opts, cols)
+}
+
+type stripLayout struct{}
+
+// PresetHorizontalStrip 将所有设备的外壳并排摆放成一条横向长条
+var PresetHorizontalStrip Layout = stripLayout{}
+
+func (stripLayout) Name() string { return "strip" }
+
+func (stripLayout) Compose(shots []DeviceShot, opts Options) (*image.RGBA, error) {
+	return composeGrid(shots, opts, len(shots))
+}
+
+// composeGrid 是网格与横向长条布局共用的排布逻辑：按 cols 列数把设备分行，
+// 每个格子取所有设备外壳中最大的宽高，设备在格子内居中摆放
+func composeGrid(shots []DeviceShot, opts Options, cols int) (*image.RGBA, error) {
+	if cols <= 0 {
+		cols = 1
+	}
+	rows := (len(shots) + cols - 1) / cols
+
+	cellW, cellH := 0, 0
+	for _, shot := range shots {
+		b := shot.Shell.Bounds()
+		if b.Dx() > cellW {
+			cellW = b.Dx()
+		}
+		if b.Dy() > cellH {
+			cellH = b.Dy()
+		}
+	}
+
+	width, height := opts.Width, opts.Height
+	if width == 0 {
+		width = cols*cellW + (cols+1)*gridPadding
+	}
+	if height == 0 {
+		height = rows*cellH + (rows+1)*gridPadding
+	}
+
+	canvas := newCanvas(width, height, opts.Background)
+
+	for i, shot := range shots {
+		dev := shot.Device
+		col, row := i%cols, i/cols
+		cellX := gridPadding + col*(cellW+gridPadding)
+		cellY := gridPadding + row*(cellH+gridPadding)
+
+		shellBounds := shot.Shell.Bounds()
+		offsetX := cellX + (cellW-shellBounds.Dx())/2
+		offsetY := cellY + (cellH-shellBounds.Dy())/2
+
+		screenW, screenH := scaled(dev.ScreenW, opts.Scale), scaled(dev.ScreenH, opts.Scale)
+		resized := imaging.Resize(shot.Screenshot, screenW, screenH, imaging.Lanczos)
+		draw.Draw(canvas, image.Rect(
+			offsetX+dev.PointX, offsetY+dev.PointY,
+			offsetX+dev.PointX+screenW, offsetY+dev.PointY+screenH,
+		), resized, image.Point{}, draw.Over)
+
+		compositeShell(canvas, shot.Shell,
+			image.Rect(offsetX, offsetY, offsetX+shellBounds.Dx(), offsetY+shellBounds.Dy()), opts.Shadow)
+	}
+
+	return canvas, nil
+}
@@ -0,0 +1,55 @@
+package compositor
+
+import (
+	"image"
+
+	"github.com/disintegration/imaging"
+	"golang.org/x/image/draw"
+)
+
+// DefaultHeroWidth 与 DefaultHeroHeight 是最初版本写死的画布尺寸，现在仅作为
+// PresetHero 在未显式传入 Options.Width/Height 时的默认值
+const (
+	DefaultHeroWidth  = 2560
+	DefaultHeroHeight = 1600
+)
+
+type heroLayout struct{}
+
+// PresetHero 复刻最初的排布方式：每个设备使用目录中人工标注的绝对坐标
+// （ScreenW/ScreenH/LayoutX/LayoutY/PointX/PointY）贴到一块固定尺寸的画布上
+var PresetHero Layout = heroLayout{}
+
+func (heroLayout) Name() string { return "hero" }
+
+func (heroLayout) Compose(shots []DeviceShot, opts Options) (*image.RGBA, error) {
+	width, height := opts.Width, opts.Height
+	if width == 0 {
+		width = DefaultHeroWidth
+	}
+	if height == 0 {
+		height = DefaultHeroHeight
+	}
+
+	canvas := newCanvas(width, height, opts.Background)
+
+	for _, shot := range shots {
+		dev := shot.Device
+		screenW, screenH := scaled(dev.ScreenW, opts.Scale), scaled(dev.ScreenH, opts.Scale)
+
+		resized := imaging.Resize(shot.Screenshot, screenW, screenH, imaging.Lanczos)
+		draw.Draw(canvas, image.Rect(dev.LayoutX, dev.LayoutY, dev.LayoutX+screenW, dev.LayoutY+screenH),
+			resized, image.Point{}, draw.Over)
+
+		shellBounds := shot.Shell.Bounds()
+		targetRect := image.Rect(
+			dev.LayoutX-dev.PointX,
+			dev.LayoutY-dev.PointY,
+			dev.LayoutX-dev.PointX+shellBounds.Dx(),
+			dev.LayoutY-dev.PointY+shellBounds.Dy(),
+		)
+		compositeShell(canvas, shot.Shell, targetRect, opts.Shadow)
+	}
+
+	return canvas, nil
+}
@@ -0,0 +1,35 @@
+// Package compositor 将各设备的截图贴入外壳并排布到一块画布上，再编码为最终的输出格式。
+// Layout 负责画布尺寸与每个设备的摆放规则，Encoder 负责把合成后的画布写成具体格式
+// （PNG/JPEG/WebP/PDF），二者都可独立扩展，不再与某一种固定的排版/格式绑定。
+package compositor
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/vespeng/multi-device-preview/devicecatalog"
+)
+
+// DeviceShot 把一个设备的截图与其外壳图片打包在一起，供 Layout 排布使用；
+// compositor 包本身不关心这两张图片是怎么来的（本地截图、HTTP 任务结果等）
+type DeviceShot struct {
+	Device     devicecatalog.Device
+	Screenshot image.Image // 已解码的设备截图（屏幕内容）
+	Shell      image.Image // 已解码的设备外壳图片
+}
+
+// Options 是排布过程中可调的画布参数，替代旧版写死在代码里的 2560x1600 等魔法数字
+type Options struct {
+	Width, Height int           // 画布尺寸；0 表示由具体布局自行计算
+	Background    color.Color   // nil 表示透明背景
+	Scale         float64       // 每个设备截图的额外缩放系数；<= 0 视为 1（不缩放）
+	Shadow        ShadowOptions // 是否在每个设备外壳下方叠加柔和投影
+}
+
+// Layout 根据一组设备截图计算画布与每个设备的摆放位置，并完成合成
+type Layout interface {
+	// Name 返回预设名称，用于 --layout 参数匹配
+	Name() string
+	// Compose 将每个设备的截图贴入其外壳，并按该布局的规则排布到一块画布上
+	Compose(shots []DeviceShot, opts Options) (*image.RGBA, error)
+}
@@ -0,0 +1,60 @@
+package compositor
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+type pdfEncoder struct{}
+
+// PDFEncoder 将每张图片渲染为独立的一页 PDF，页面尺寸与图片的像素尺寸一致
+// （按 72dpi 换算，1px = 1pt）
+var PDFEncoder MultiPageEncoder = pdfEncoder{}
+
+func (pdfEncoder) Name() string        { return "pdf" }
+func (pdfEncoder) Extension() string   { return ".pdf" }
+func (pdfEncoder) SupportsAlpha() bool { return true }
+
+// Encode 在只有一页内容时，把单张画布当作一页 PDF 输出
+func (e pdfEncoder) Encode(w io.Writer, canvas image.Image) error {
+	return e.EncodePages(w, []NamedImage{{Name: "preview", Image: canvas}})
+}
+
+// EncodePages 为每张图片各生成一页
+func (pdfEncoder) EncodePages(w io.Writer, pages []NamedImage) error {
+	if len(pages) == 0 {
+		return fmt.Errorf("pdf 输出至少需要一页内容")
+	}
+
+	first := pages[0].Image.Bounds()
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "pt",
+		SizeStr:        "",
+		Size:           gofpdf.SizeType{Wd: float64(first.Dx()), Ht: float64(first.Dy())},
+	})
+
+	for _, page := range pages {
+		bounds := page.Image.Bounds()
+		pdf.AddPageFormat("P", gofpdf.SizeType{Wd: float64(bounds.Dx()), Ht: float64(bounds.Dy())})
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, page.Image); err != nil {
+			return fmt.Errorf("❌ 编码 PDF 页面失败 (%s): %w", page.Name, err)
+		}
+
+		opts := gofpdf.ImageOptions{ImageType: "PNG"}
+		pdf.RegisterImageOptionsReader(page.Name, opts, &buf)
+		pdf.ImageOptions(page.Name, 0, 0, float64(bounds.Dx()), float64(bounds.Dy()), false, opts, 0, "")
+	}
+
+	if err := pdf.Output(w); err != nil {
+		return fmt.Errorf("❌ 写出 PDF 失败: %w", err)
+	}
+	return nil
+}
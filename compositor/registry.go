@@ -0,0 +1,23 @@
+package compositor
+
+import "fmt"
+
+// Layouts 是可通过 --layout 参数选择的内置布局预设
+var Layouts = map[string]Layout{
+	PresetHero.Name():            PresetHero,
+	PresetGrid.Name():            PresetGrid,
+	PresetHorizontalStrip.Name(): PresetHorizontalStrip,
+	PresetSingleDevice.Name():    PresetSingleDevice,
+}
+
+// LookupLayout 按名称查找内置布局预设；空字符串返回默认的 PresetHero
+func LookupLayout(name string) (Layout, error) {
+	if name == "" {
+		return PresetHero, nil
+	}
+	layout, ok := Layouts[name]
+	if !ok {
+		return nil, fmt.Errorf("未知的布局预设: %s", name)
+	}
+	return layout, nil
+}
@@ -0,0 +1,46 @@
+package compositor
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/disintegration/imaging"
+	"golang.org/x/image/draw"
+)
+
+type singleLayout struct{}
+
+// PresetSingleDevice 只渲染第一个设备，画布默认按该设备外壳的原始尺寸，
+// 适合单独导出某一机型（也是 PDF 多页输出中每一页所使用的布局）
+var PresetSingleDevice Layout = singleLayout{}
+
+func (singleLayout) Name() string { return "single" }
+
+func (singleLayout) Compose(shots []DeviceShot, opts Options) (*image.RGBA, error) {
+	if len(shots) == 0 {
+		return nil, fmt.Errorf("single 布局至少需要一个设备")
+	}
+
+	shot := shots[0]
+	dev := shot.Device
+	shellBounds := shot.Shell.Bounds()
+
+	width, height := opts.Width, opts.Height
+	if width == 0 {
+		width = shellBounds.Dx()
+	}
+	if height == 0 {
+		height = shellBounds.Dy()
+	}
+
+	canvas := newCanvas(width, height, opts.Background)
+
+	screenW, screenH := scaled(dev.ScreenW, opts.Scale), scaled(dev.ScreenH, opts.Scale)
+	resized := imaging.Resize(shot.Screenshot, screenW, screenH, imaging.Lanczos)
+	draw.Draw(canvas, image.Rect(dev.PointX, dev.PointY, dev.PointX+screenW, dev.PointY+screenH),
+		resized, image.Point{}, draw.Over)
+
+	compositeShell(canvas, shot.Shell, image.Rect(0, 0, shellBounds.Dx(), shellBounds.Dy()), opts.Shadow)
+
+	return canvas, nil
+}
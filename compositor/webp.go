@@ -0,0 +1,26 @@
+package compositor
+
+import (
+	"image"
+	"io"
+
+	"github.com/chai2010/webp"
+)
+
+type webpEncoder struct{ quality float32 }
+
+// NewWebPEncoder 创建一个有损 WebP 编码器，quality 取值 1-100，超出范围时回退为 90
+func NewWebPEncoder(quality int) Encoder {
+	q := float32(quality)
+	if q <= 0 || q > 100 {
+		q = 90
+	}
+	return webpEncoder{quality: q}
+}
+
+func (webpEncoder) Name() string        { return "webp" }
+func (webpEncoder) Extension() string   { return ".webp" }
+func (webpEncoder) SupportsAlpha() bool { return false }
+func (e webpEncoder) Encode(w io.Writer, canvas image.Image) error {
+	return webp.Encode(w, canvas, &webp.Options{Quality: e.quality})
+}
@@ -1,24 +1,26 @@
 package main
 
-import "embed"
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/vespeng/multi-device-preview/devicecatalog"
+)
 
 //go:embed devices/*.png
 var deviceFiles embed.FS
 
-// DeviceParams 定义设备参数
-type DeviceParams struct {
-	Name       string // 设备名称
-	DevicePath string // 设备外壳路径
-	ScreenW    int    // 屏幕区域宽度
-	ScreenH    int    // 屏幕区域高度
-	PointX     int    // 屏幕区域左上角 X 偏移
-	PointY     int    // 屏幕区域左上角 Y 偏移
-	LayoutX    int    // 布局X
-	LayoutY    int    // 布局Y
-}
+// DeviceParams 是设备定义在主程序中使用的类型，直接复用 devicecatalog.Device，
+// 避免在加载目录与业务逻辑之间再做一次字段搬运。
+type DeviceParams = devicecatalog.Device
 
-// Devices 设备配置实例
-var Devices = []DeviceParams{
+// devicesConfigFileName 是设备目录配置文件相对于可执行文件的文件名
+const devicesConfigFileName = "devices.yaml"
+
+// DefaultDevices 是内置的设备集合，在可执行文件同级目录下找不到 devices.yaml 时作为兜底
+var DefaultDevices = []DeviceParams{
 	{
 		Name:       "MacBook 16 Pro",
 		DevicePath: "devices/macbook-pro-16.png",
@@ -38,6 +40,9 @@ var Devices = []DeviceParams{
 		PointY:     28,
 		LayoutX:    280,
 		LayoutY:    520,
+		Emulation: devicecatalog.Emulation{
+			Preset: "iPad Pro",
+		},
 	},
 	{
 		Name:       "iPhone 15 Pro",
@@ -48,5 +53,31 @@ var Devices = []DeviceParams{
 		PointY:     11,
 		LayoutX:    720,
 		LayoutY:    780,
+		// todo 这里直接使用 15 Pro 模拟参数不对，暂时用 12 Pro 的内置预设
+		Emulation: devicecatalog.Emulation{
+			Preset: "iPhone 12 Pro",
+		},
+		CornerRadius: 120.0,
 	},
 }
+
+// Devices 是实际使用的设备集合，由 LoadDevices 在程序启动时填充
+var Devices []DeviceParams
+
+// LoadDevices 从可执行文件同级目录下的 devices.yaml 加载设备目录（按 YAML 解析，
+// 文件内容也可以直接写成 JSON；不会查找 devices.json），文件不存在时回退到内置的
+// DefaultDevices，使新增设备无需重新编译。
+func LoadDevices() ([]DeviceParams, error) {
+	execPath, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("❌ 获取可执行文件路径失败: %w", err)
+	}
+
+	configPath := filepath.Join(filepath.Dir(execPath), devicesConfigFileName)
+	cat, err := devicecatalog.Load(configPath, DefaultDevices)
+	if err != nil {
+		return nil, err
+	}
+
+	return cat.Devices, nil
+}
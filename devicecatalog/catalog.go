@@ -0,0 +1,123 @@
+// Package devicecatalog 从外部配置文件（devices.yaml / devices.json）加载设备目录，
+// 包括外壳图片路径、屏幕区域、布局坐标与 chromedp 模拟参数，使新增一款设备
+// （手机、平板）无需重新编译二进制。
+package devicecatalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Emulation 描述 chromedp.Emulate 所需的模拟参数。
+// 优先使用 Preset（对应 chromedp/device 包内置的设备，如 "iPhone 12 Pro"）；
+// 未命中内置预设时，使用下面的显式字段拼装模拟参数。
+type Emulation struct {
+	Preset    string  `yaml:"preset,omitempty" json:"preset,omitempty"`
+	Width     int64   `yaml:"width,omitempty" json:"width,omitempty"`
+	Height    int64   `yaml:"height,omitempty" json:"height,omitempty"`
+	Scale     float64 `yaml:"scale,omitempty" json:"scale,omitempty"` // 设备像素比（DPR）
+	UserAgent string  `yaml:"user_agent,omitempty" json:"user_agent,omitempty"`
+	Mobile    bool    `yaml:"mobile,omitempty" json:"mobile,omitempty"`
+	Touch     bool    `yaml:"touch,omitempty" json:"touch,omitempty"`
+	Landscape bool    `yaml:"landscape,omitempty" json:"landscape,omitempty"`
+}
+
+// Cookie 描述截图前需要预置的一条 Cookie。Domain 为空时会落在请求 URL 的域名上
+type Cookie struct {
+	Name   string `yaml:"name" json:"name"`
+	Value  string `yaml:"value" json:"value"`
+	Domain string `yaml:"domain,omitempty" json:"domain,omitempty"`
+	Path   string `yaml:"path,omitempty" json:"path,omitempty"`
+}
+
+// BasicAuth 描述截图前需要完成的 HTTP 基础认证凭据
+type BasicAuth struct {
+	Username string `yaml:"username" json:"username"`
+	Password string `yaml:"password" json:"password"`
+}
+
+// 等待页面就绪的策略，对应 Wait.Strategy
+const (
+	WaitDelay    = "delay"    // 固定等待 DelayMs（默认策略）
+	WaitSelector = "selector" // 等待 Selector 对应的元素可见
+	WaitIdle     = "idle"     // 等待 IdleTimeoutMs 这段时间内没有新的网络请求完成
+)
+
+// Wait 描述截图前等待页面就绪的策略，替代写死的 3 秒等待：
+// 简单页面常常等太久，背后有登录态的 SPA 又经常等不够
+type Wait struct {
+	Strategy      string `yaml:"strategy,omitempty" json:"strategy,omitempty"`
+	Selector      string `yaml:"selector,omitempty" json:"selector,omitempty"`
+	DelayMs       int    `yaml:"delay_ms,omitempty" json:"delay_ms,omitempty"`
+	IdleTimeoutMs int    `yaml:"idle_timeout_ms,omitempty" json:"idle_timeout_ms,omitempty"`
+}
+
+// Device 描述目录中的一个设备定义
+type Device struct {
+	Name         string  `yaml:"name" json:"name"`
+	DevicePath   string  `yaml:"device_path" json:"device_path"`
+	ScreenW      int     `yaml:"screen_w" json:"screen_w"`
+	ScreenH      int     `yaml:"screen_h" json:"screen_h"`
+	PointX       int     `yaml:"point_x" json:"point_x"`
+	PointY       int     `yaml:"point_y" json:"point_y"`
+	LayoutX      int     `yaml:"layout_x" json:"layout_x"`
+	LayoutY      int     `yaml:"layout_y" json:"layout_y"`
+	CornerRadius float64 `yaml:"corner_radius,omitempty" json:"corner_radius,omitempty"`
+	// FullPage 为 true 时截取整个可滚动页面，而不仅仅是首屏视口
+	FullPage  bool      `yaml:"full_page,omitempty" json:"full_page,omitempty"`
+	Emulation Emulation `yaml:"emulation,omitempty" json:"emulation,omitempty"`
+	// Cookies 在导航前预置到浏览器中，常用于跳过登录态
+	Cookies []Cookie `yaml:"cookies,omitempty" json:"cookies,omitempty"`
+	// Headers 是导航时附带的额外 HTTP 请求头
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	// BasicAuth 非空时在导航前完成一次 HTTP 基础认证
+	BasicAuth *BasicAuth `yaml:"basic_auth,omitempty" json:"basic_auth,omitempty"`
+	// PostLoadScript 在页面等待就绪后、截图前执行一段 JS，常用于隐藏 Cookie 弹窗或
+	// 写入 localStorage 令牌
+	PostLoadScript string `yaml:"post_load_script,omitempty" json:"post_load_script,omitempty"`
+	// Wait 描述截图前等待页面就绪的策略，零值等价于 WaitDelay + 默认延时
+	Wait Wait `yaml:"wait,omitempty" json:"wait,omitempty"`
+}
+
+// Catalog 是设备定义的集合
+type Catalog struct {
+	Devices []Device `yaml:"devices" json:"devices"`
+}
+
+// Load 从 path 指向的 devices.yaml/devices.json 加载设备目录。
+// 文件不存在时返回以 fallback 填充的目录（通常是内置的默认设备集）；
+// 文件存在但内容为空，或未声明任何设备时同样回退到 fallback。
+func Load(path string, fallback []Device) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Catalog{Devices: fallback}, nil
+		}
+		return nil, fmt.Errorf("读取设备目录失败 (%s): %w", path, err)
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return &Catalog{Devices: fallback}, nil
+	}
+
+	cat := &Catalog{}
+	if filepath.Ext(path) == ".json" {
+		err = json.Unmarshal(data, cat)
+	} else {
+		err = yaml.Unmarshal(data, cat)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析设备目录失败 (%s): %w", path, err)
+	}
+
+	if len(cat.Devices) == 0 {
+		cat.Devices = fallback
+	}
+
+	return cat, nil
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"mime"
+	"sync"
+	"time"
+
+	"github.com/vespeng/multi-device-preview/compositor"
+	"github.com/vespeng/multi-device-preview/devicecatalog"
+)
+
+// JobStatus 描述一次异步预览任务所处的阶段
+type JobStatus string
+
+const (
+	JobPending JobStatus = "pending"
+	JobRunning JobStatus = "running"
+	JobDone    JobStatus = "done"
+	JobFailed  JobStatus = "failed"
+)
+
+// PreviewRequest 对应 POST /preview 的请求体
+type PreviewRequest struct {
+	URL          string   `json:"url"`
+	Devices      []string `json:"devices"`
+	WaitSelector string   `json:"wait_selector"`
+	DelayMs      int      `json:"delay_ms"`
+	// FullPage 为 true 时对请求中的所有设备都按整页模式截图，即使设备配置中未开启
+	FullPage  bool   `json:"full_page"`
+	UserAgent string `json:"user_agent"`
+	// ViewportScale 覆盖设备模拟参数中的 DPR（设备像素比），为 0 时使用设备配置自带的值
+	ViewportScale float64 `json:"viewport_scale"`
+	// Layout 对应 compositor 的布局预设名称（hero/grid/strip/single），为空时使用 hero
+	Layout string `json:"layout"`
+	// Format 对应 compositor 的输出格式（png/jpeg/webp/pdf），为空时使用 png
+	Format string `json:"format"`
+	// Quality 是 jpeg/webp 的压缩质量（1-100），为 0 时使用各自的默认值
+	Quality int `json:"quality"`
+	// Shadow 为 true 时在每个设备外壳下方叠加柔和投影
+	Shadow bool `json:"shadow"`
+	// ColorScheme 强制页面配色方案: dark/light，为空时跟随站点默认
+	ColorScheme string `json:"color_scheme"`
+	// Width/Height 覆盖画布尺寸（像素），为 0 时由所选布局自行决定
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// Background 是画布背景色: white/black/transparent 或 #RRGGBB(AA)，为空时
+	// PNG/PDF 默认透明、JPEG/WebP 默认白色
+	Background string `json:"background"`
+	// Scale 是每个设备截图的额外缩放系数，<= 0 时视为 1（不缩放）
+	Scale float64 `json:"scale"`
+	// Async 为 true 时立即返回任务 id，由调用方轮询 GET /preview/:id 获取结果；
+	// 默认同步等待任务完成后直接返回按 Format 编码的结果。
+	Async bool `json:"async"`
+}
+
+// Job 表示一次预览生成任务。status/result/contentType/err 由 run 在 worker 协程中
+// 写入，而异步轮询 GET /preview/:id 的 HTTP handler 协程可能在任务完成前并发读取
+// 这些字段（done 只对同步 Wait 路径建立 happens-before），因此一律通过 mu 保护，
+// 只能经 Snapshot 读取。
+type Job struct {
+	ID        string
+	Req       PreviewRequest
+	CreatedAt time.Time
+	done      chan struct{}
+
+	mu          sync.Mutex
+	status      JobStatus
+	result      []byte
+	contentType string
+	err         string
+}
+
+// JobSnapshot 是 Job 状态在某一时刻的只读快照，可安全地被多个协程并发持有
+type JobSnapshot struct {
+	Status      JobStatus
+	Result      []byte
+	ContentType string
+	Err         string
+}
+
+// newJob 创建一个待处理的任务
+func newJob(req PreviewRequest) *Job {
+	return &Job{
+		ID:        generateJobID(),
+		Req:       req,
+		status:    JobPending,
+		CreatedAt: time.Now(),
+		done:      make(chan struct{}),
+	}
+}
+
+// run 在给定的浏览器标签页上下文中渲染预览图，并写回结果
+func (j *Job) run(tabCtx context.Context) {
+	j.mu.Lock()
+	j.status = JobRunning
+	j.mu.Unlock()
+
+	data, contentType, err := renderPreview(tabCtx, j.Req)
+
+	j.mu.Lock()
+	if err != nil {
+		j.status = JobFailed
+		j.err = err.Error()
+	} else {
+		j.status = JobDone
+		j.result = data
+		j.contentType = contentType
+	}
+	j.mu.Unlock()
+
+	close(j.done)
+}
+
+// Snapshot 返回任务当前状态的只读快照
+func (j *Job) Snapshot() JobSnapshot {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return JobSnapshot{Status: j.status, Result: j.result, ContentType: j.contentType, Err: j.err}
+}
+
+// Wait 阻塞直到任务完成，或 ctx 被取消/超时
+func (j *Job) Wait(ctx context.Context) error {
+	select {
+	case <-j.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// generateJobID 生成一个随机的任务 id
+func generateJobID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// jobStore 维护任务 id 到 Job 的映射，供 GET /preview/:id 异步查询使用
+type jobStore struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+func newJobStore() *jobStore {
+	return &jobStore{jobs: make(map[string]*Job)}
+}
+
+func (s *jobStore) put(job *Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = job
+}
+
+func (s *jobStore) get(id string) (*Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	return job, ok
+}
+
+// renderPreview 使用给定的浏览器标签页上下文渲染一次预览请求，按请求中的 layout/format
+// 排布并编码，返回编码后的数据及对应的 Content-Type
+func renderPreview(tabCtx context.Context, req PreviewRequest) ([]byte, string, error) {
+	devices := selectDevices(req.Devices)
+	if len(devices) == 0 {
+		return nil, "", fmt.Errorf("没有匹配的设备: %v", req.Devices)
+	}
+
+	layout, err := compositor.LookupLayout(req.Layout)
+	if err != nil {
+		return nil, "", err
+	}
+	encoder, err := compositor.LookupEncoder(req.Format, req.Quality)
+	if err != nil {
+		return nil, "", err
+	}
+
+	shots := make(map[string]*image.RGBA, len(devices))
+	for _, dev := range devices {
+		dev = applyRequestOverrides(dev, req)
+
+		img, err := takeScreenshotForDevice(tabCtx, req.URL, dev, dev.FullPage || req.FullPage, req.ColorScheme)
+		if err != nil {
+			return nil, "", fmt.Errorf("❌ 截图失败 (%s): %w", dev.Name, err)
+		}
+		shots[dev.Name] = img
+	}
+
+	background, err := compositor.ParseBackground(req.Background, encoder.SupportsAlpha())
+	if err != nil {
+		return nil, "", err
+	}
+	canvasOpts := compositor.Options{
+		Width:      req.Width,
+		Height:     req.Height,
+		Background: background,
+		Scale:      req.Scale,
+		Shadow:     compositor.ShadowOptions{Enabled: req.Shadow},
+	}
+
+	var buf bytes.Buffer
+	if multi, ok := encoder.(compositor.MultiPageEncoder); ok {
+		pages, err := renderPDFPages(devices, shots, canvasOpts)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := multi.EncodePages(&buf, pages); err != nil {
+			return nil, "", fmt.Errorf("❌ 编码失败: %w", err)
+		}
+	} else {
+		canvas, err := renderCanvas(devices, shots, layout, canvasOpts)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := encoder.Encode(&buf, canvas); err != nil {
+			return nil, "", fmt.Errorf("❌ 编码失败: %w", err)
+		}
+	}
+
+	contentType := mime.TypeByExtension(encoder.Extension())
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return buf.Bytes(), contentType, nil
+}
+
+// applyRequestOverrides 用请求中的字段覆盖设备配置里对应的默认值：WaitSelector/DelayMs
+// 覆盖设备的等待策略，UserAgent 覆盖设备的模拟 UA，ViewportScale 覆盖设备的 DPR，
+// 三者都只在请求中给出非零值时生效
+func applyRequestOverrides(dev DeviceParams, req PreviewRequest) DeviceParams {
+	if req.WaitSelector != "" {
+		dev.Wait = devicecatalog.Wait{Strategy: devicecatalog.WaitSelector, Selector: req.WaitSelector}
+	} else if req.DelayMs > 0 {
+		dev.Wait = devicecatalog.Wait{Strategy: devicecatalog.WaitDelay, DelayMs: req.DelayMs}
+	}
+
+	if req.UserAgent != "" {
+		dev.Emulation.UserAgent = req.UserAgent
+	}
+
+	if req.ViewportScale > 0 {
+		dev.Emulation.Scale = req.ViewportScale
+	}
+
+	return dev
+}
+
+// selectDevices 根据请求中的设备名称过滤设备列表，未指定时返回全部设备
+func selectDevices(names []string) []DeviceParams {
+	if len(names) == 0 {
+		return Devices
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, n := range names {
+		wanted[n] = true
+	}
+
+	var out []DeviceParams
+	for _, dev := range Devices {
+		if wanted[dev.Name] {
+			out = append(out, dev)
+		}
+	}
+	return out
+}
@@ -3,22 +3,33 @@ package main
 import (
 	"bytes"
 	"context"
+	"flag"
 	"fmt"
 	"image"
-	"image/color"
-	"image/png"
 	"os"
 	"path/filepath"
 	"runtime"
 	"sync"
 	"time"
 
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
 	"github.com/chromedp/chromedp/device"
-	"github.com/disintegration/imaging"
+	"github.com/vespeng/multi-device-preview/compositor"
+	"github.com/vespeng/multi-device-preview/devicecatalog"
 	"golang.org/x/image/draw"
 )
 
+// fullPageScrollStep 是整页截图在每次滚动后等待懒加载内容触发的停顿时间
+const fullPageScrollStep = 200 * time.Millisecond
+
+// fullPageMaxScrollSteps 是整页截图滚动到底部的最大步数，避免无限滚动页面导致死循环
+const fullPageMaxScrollSteps = 50
+
 var (
 	wg sync.WaitGroup
 	mu sync.Mutex
@@ -29,36 +40,128 @@ func main() {
 	if len(os.Args) < 2 {
 		fmt.Println("请提供需要生成预览图的 UR L地址")
 		fmt.Println("用法: program_name <url>")
+		fmt.Println("      program_name serve --addr :8080")
 		fmt.Println("示例: program_name http://localhost:8080/")
 		os.Exit(1)
 	}
 
+	// 加载设备目录（devices.yaml 存在则使用其内容，否则回退到内置设备集）
+	devices, err := LoadDevices()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	Devices = devices
+
+	// serve 子命令：以 HTTP 微服务模式常驻运行
+	if os.Args[1] == "serve" {
+		if err := runServeCommand(os.Args[2:]); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// 从命令行参数获取URL
 	url := os.Args[1]
 
-	err := execute(url)
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	layoutName := fs.String("layout", "", "画布布局预设: hero(默认)/grid/strip/single")
+	format := fs.String("format", "png", "输出格式: png(默认)/jpeg/webp/pdf")
+	quality := fs.Int("quality", 90, "jpeg/webp 的压缩质量（1-100）")
+	shadow := fs.Bool("shadow", false, "是否在每个设备外壳下方叠加柔和投影")
+	colorScheme := fs.String("color-scheme", "", "强制页面配色方案: dark/light，留空则跟随站点默认")
+	width := fs.Int("width", 0, "画布宽度（像素），0 表示由所选布局自行决定")
+	height := fs.Int("height", 0, "画布高度（像素），0 表示由所选布局自行决定")
+	background := fs.String("background", "", "画布背景色: white/black/transparent 或 #RRGGBB(AA)，留空时 PNG/PDF 默认透明、JPEG/WebP 默认白色")
+	scale := fs.Float64("scale", 0, "每个设备截图的额外缩放系数，<= 0 视为 1（不缩放）")
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if *colorScheme != "" && *colorScheme != "dark" && *colorScheme != "light" {
+		fmt.Println("❌ --color-scheme 只能是 dark 或 light")
+		os.Exit(1)
+	}
+
+	err = execute(url, ExecuteOptions{
+		Layout:      *layoutName,
+		Format:      *format,
+		Quality:     *quality,
+		Shadow:      *shadow,
+		ColorScheme: *colorScheme,
+		Width:       *width,
+		Height:      *height,
+		Background:  *background,
+		Scale:       *scale,
+	})
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 }
 
+// runServeCommand 解析 serve 子命令的参数并启动 HTTP 微服务
+func runServeCommand(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "HTTP 监听地址")
+	concurrency := fs.Int("concurrency", 2, "浏览器标签页并发数（同时处理的任务数）")
+	timeout := fs.Duration("timeout", 30*time.Second, "单次预览生成的超时时间")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	browserPath, err := detectBrowserPath()
+	if err != nil {
+		return fmt.Errorf("❌ 无法获取浏览器路径: " + err.Error())
+	}
+	fmt.Println("🔍 使用浏览器:", browserPath)
+
+	return runServer(browserPath, ServeOptions{
+		Addr:           *addr,
+		Concurrency:    *concurrency,
+		RequestTimeout: *timeout,
+	})
+}
+
+// ExecuteOptions 描述 CLI 生成模式下可调的布局/输出格式参数
+type ExecuteOptions struct {
+	Layout      string  // 画布布局预设名称，空字符串使用默认的 hero
+	Format      string  // 输出格式: png(默认)/jpeg/webp/pdf
+	Quality     int     // jpeg/webp 的压缩质量（1-100）
+	Shadow      bool    // 是否在每个设备外壳下方叠加柔和投影
+	ColorScheme string  // 强制页面配色方案: dark/light，空字符串跟随站点默认
+	Width       int     // 画布宽度（像素），0 表示由所选布局自行决定
+	Height      int     // 画布高度（像素），0 表示由所选布局自行决定
+	Background  string  // 画布背景色: white/black/transparent 或 #RRGGBB(AA)，空字符串按编码格式选择默认值
+	Scale       float64 // 每个设备截图的额外缩放系数，<= 0 视为 1（不缩放）
+}
+
 // execute 执行预览图生成
-func execute(url string) error {
+func execute(url string, opts ExecuteOptions) error {
 	browserPath, err := detectBrowserPath()
 	if err != nil {
 		return fmt.Errorf("❌ 无法获取浏览器路径: " + err.Error())
 	}
 	fmt.Println("🔍 使用浏览器:", browserPath)
 
+	layout, err := compositor.LookupLayout(opts.Layout)
+	if err != nil {
+		return err
+	}
+	encoder, err := compositor.LookupEncoder(opts.Format, opts.Quality)
+	if err != nil {
+		return err
+	}
+
 	// 初始化浏览器分配器上下文
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+	allocOpts := append(chromedp.DefaultExecAllocatorOptions[:],
 		chromedp.ExecPath(browserPath),
 		chromedp.NoFirstRun,
 		chromedp.NoDefaultBrowserCheck,
 		chromedp.Headless,
 	)
-	allocCtx, cancel := chromedp.NewExecAllocator(context.Background(), opts...)
+	allocCtx, cancel := chromedp.NewExecAllocator(context.Background(), allocOpts...)
 	defer cancel()
 
 	// 创建一个 map 用于保存每个设备截图
@@ -74,7 +177,7 @@ func execute(url string) error {
 			ctx, cancel := chromedp.NewContext(allocCtx)
 			defer cancel()
 
-			img, err := takeScreenshotForDevice(ctx, url, device.ScreenW, device.ScreenH, device.Name)
+			img, err := takeScreenshotForDevice(ctx, url, device, device.FullPage, opts.ColorScheme)
 			if err != nil {
 				fmt.Printf("❌ 截图失败 (%s): %v\n", device.Name, err)
 				return
@@ -90,67 +193,52 @@ func execute(url string) error {
 
 	wg.Wait()
 
-	// Step 2: 创建透明画布
-	canvas := imaging.New(2560, 1600, color.White)
-
-	// Step 3: 所有截图贴入到画布
+	// Step 2 & 3: 合成画布（设备外壳 + 截图），并按所选格式编码
 	fmt.Println("🎨 正在生成预览图...")
-	for _, dev := range Devices {
-		screenshot := deviceScreenshots[dev.Name]
-		resized := imaging.Resize(screenshot, dev.ScreenW, dev.ScreenH, imaging.Lanczos)
-		draw.Draw(canvas, image.Rect(dev.LayoutX, dev.LayoutY,
-			dev.LayoutX+dev.ScreenW, dev.LayoutY+dev.ScreenH),
-			resized, image.Point{}, draw.Over)
-
-		// 读取设备图片
-		data, err := deviceFiles.ReadFile(dev.DevicePath)
-		if err != nil {
-			return fmt.Errorf("❌ 读取设备图片失败 (%s): %v", dev.DevicePath, err)
-		}
 
-		// 解码图片数据
-		deviceImg, _, err := image.Decode(bytes.NewReader(data))
-		if err != nil {
-			return fmt.Errorf("❌ 解码设备图片失败 (%s): %v", dev.DevicePath, err)
-		}
-
-		// 转换为 RGBA 格式以便绘制
-		deviceBounds := deviceImg.Bounds()
-		devicePath := image.NewRGBA(deviceBounds)
-		draw.Draw(devicePath, deviceBounds, deviceImg, deviceBounds.Min, draw.Src)
-
-		// 将外壳覆盖到画布的对应位置（LayoutX/Y）
-		targetRect := image.Rect(
-			dev.LayoutX-dev.PointX,
-			dev.LayoutY-dev.PointY,
-			dev.LayoutX-dev.PointX+deviceBounds.Dx(),
-			dev.LayoutY-dev.PointY+deviceBounds.Dy(),
-		)
-
-		draw.Draw(canvas, targetRect, devicePath, image.Point{}, draw.Over)
-	}
-
-	// Step 4: 保存
-	// 获取可执行文件路径
 	execPath, err := os.Executable()
 	if err != nil {
 		return fmt.Errorf("❌ 获取可执行文件路径失败: " + err.Error())
 	}
-
-	// 获取可执行文件所在目录
 	execDir := filepath.Dir(execPath)
+	outFile := filepath.Join(execDir, "preview"+encoder.Extension())
 
-	// 构造输出文件路径（与可执行文件同级目录）
-	outFile := filepath.Join(execDir, "preview.png")
 	f, err := os.Create(outFile)
 	if err != nil {
 		return fmt.Errorf("❌ 截图保存失败: " + err.Error())
 	}
 	defer f.Close()
 
-	if err := png.Encode(f, canvas); err != nil {
-		return fmt.Errorf("❌ 截图保存失败: " + err.Error())
+	background, err := compositor.ParseBackground(opts.Background, encoder.SupportsAlpha())
+	if err != nil {
+		return err
 	}
+	canvasOpts := compositor.Options{
+		Width:      opts.Width,
+		Height:     opts.Height,
+		Background: background,
+		Scale:      opts.Scale,
+		Shadow:     compositor.ShadowOptions{Enabled: opts.Shadow},
+	}
+
+	if multi, ok := encoder.(compositor.MultiPageEncoder); ok {
+		pages, err := renderPDFPages(Devices, deviceScreenshots, canvasOpts)
+		if err != nil {
+			return err
+		}
+		if err := multi.EncodePages(f, pages); err != nil {
+			return fmt.Errorf("❌ 截图保存失败: " + err.Error())
+		}
+	} else {
+		canvas, err := renderCanvas(Devices, deviceScreenshots, layout, canvasOpts)
+		if err != nil {
+			return err
+		}
+		if err := encoder.Encode(f, canvas); err != nil {
+			return fmt.Errorf("❌ 截图保存失败: " + err.Error())
+		}
+	}
+
 	fmt.Println("✅ 预览图生成成功:", outFile)
 
 	return nil
@@ -193,45 +281,244 @@ func detectBrowserPath() (string, error) {
 	return "", fmt.Errorf("未找到可用的 Chromium 内核浏览器（Chrome / Edge）请安装后重试。")
 }
 
-// takeScreenshotForMacBook 截图
-func takeScreenshotForDevice(ctx context.Context, url string, width, height int, deviceName string) (*image.RGBA, error) {
-	var buf []byte
+// presetDevices 维护内置设备名到 chromedp/device 预设的映射，供 devices.yaml 中的
+// emulation.preset 字段引用，无需了解 chromedp/device 包内部命名即可复用官方模拟参数。
+// 覆盖 chromedp/device 中常用的一批机型；devices.yaml 里若引用了不在此列表中的机型，
+// 应通过 emulation 的显式 width/height/scale 等字段自行拼装，而不是指望这里能识别任意
+// preset 名称。
+var presetDevices = map[string]device.Info{
+	"iPad Pro":      device.IPadPro,
+	"iPad":          device.IPad,
+	"iPad Mini":     device.IPadMini,
+	"iPhone 12 Pro": device.IPhone12Pro,
+	"iPhone 8":      device.IPhone8,
+	"iPhone 8 Plus": device.IPhone8Plus,
+	"iPhone 7":      device.IPhone7,
+	"iPhone SE":     device.IPhoneSE,
+	"iPhone X":      device.IPhoneX,
+	"Pixel 2":       device.Pixel2,
+	"Pixel 2 XL":    device.Pixel2XL,
+	"Pixel 3":       device.Pixel3,
+	"Nexus 5X":      device.Nexus5X,
+	"Nexus 6P":      device.Nexus6P,
+	"Galaxy S5":     device.GalaxyS5,
+}
 
-	switch deviceName {
-	case "MacBook 16 Pro":
-		err := chromedp.Run(ctx,
-			chromedp.EmulateViewport(int64(width), int64(height)),
-			chromedp.Navigate(url),
-			chromedp.Sleep(3*time.Second),
-			chromedp.WaitVisible("body", chromedp.ByQuery),
-			chromedp.CaptureScreenshot(&buf),
-		)
-		if err != nil {
-			return nil, err
+// emulateAction 根据设备的模拟参数构建 chromedp.Emulate 动作，并返回实际生效的
+// device.Info（供整页截图重设视口高度时保留相同的 DPR/mobile/touch，见
+// expandToFullPage）。
+func emulateAction(em devicecatalog.Emulation, fallbackW, fallbackH int) (chromedp.Action, device.Info, error) {
+	info, err := resolveDeviceInfo(em, fallbackW, fallbackH)
+	if err != nil {
+		return nil, device.Info{}, err
+	}
+	return chromedp.Emulate(info), info, nil
+}
+
+// resolveDeviceInfo 解析设备模拟参数对应的 device.Info：优先使用内置 preset；
+// preset 未命中但提供了显式宽高时，用这些字段自行拼装；两者都没有时仅按屏幕区域尺寸
+// 设置视口（等价于旧版 MacBook 分支的行为）。preset 未命中且没有显式宽高兜底时返回
+// 错误，避免静默退化成一个尺寸、DPR 都不对的视口。
+// 无论走哪个分支，最后都会用 em.Scale/em.UserAgent（非零值时）覆盖解析结果——否则
+// preset 命中的设备会完全忽略这两个字段，导致 viewport_scale/user_agent 的请求级
+// 覆盖对 iPad Pro/iPhone 12 Pro 这类走 preset 的真实设备全部失效。
+func resolveDeviceInfo(em devicecatalog.Emulation, fallbackW, fallbackH int) (device.Info, error) {
+	var info device.Info
+
+	switch {
+	case em.Preset != "":
+		preset, ok := presetDevices[em.Preset]
+		if !ok {
+			if em.Width == 0 || em.Height == 0 {
+				return device.Info{}, fmt.Errorf("未知的设备模拟预设 %q，且未提供显式的 width/height 作为兜底", em.Preset)
+			}
+			info = device.Info{Width: em.Width, Height: em.Height, Landscape: em.Landscape, Mobile: em.Mobile, Touch: em.Touch}
+		} else {
+			info = preset
 		}
-	case "iPad Pro 13":
-		err := chromedp.Run(ctx,
-			chromedp.Emulate(device.IPadPro),
-			chromedp.Navigate(url),
-			chromedp.Sleep(3*time.Second),
-			chromedp.WaitVisible("body", chromedp.ByQuery),
-			chromedp.CaptureScreenshot(&buf),
-		)
-		if err != nil {
-			return nil, err
+	case em.Width != 0 && em.Height != 0:
+		info = device.Info{Width: em.Width, Height: em.Height, Landscape: em.Landscape, Mobile: em.Mobile, Touch: em.Touch}
+	default:
+		info = device.Info{Width: int64(fallbackW), Height: int64(fallbackH), Scale: 1}
+	}
+
+	if em.Scale > 0 {
+		info.Scale = em.Scale
+	}
+	if em.UserAgent != "" {
+		info.UserAgent = em.UserAgent
+	}
+
+	return info, nil
+}
+
+// colorSchemeMediaAction 在导航前设置 prefers-color-scheme 模拟媒体特性，使页面按
+// 指定的配色方案（dark/light）渲染；colorScheme 为空时不做任何改动，跟随站点默认。
+func colorSchemeMediaAction(colorScheme string) chromedp.Action {
+	return emulation.SetEmulatedMedia().WithFeatures([]*emulation.MediaFeature{
+		{Name: "prefers-color-scheme", Value: colorScheme},
+	})
+}
+
+// setCookiesAction 在导航前预置设备配置中声明的 Cookie；Cookie 未指定 Domain 时落在
+// pageURL 的域名上
+func setCookiesAction(pageURL string, cookies []devicecatalog.Cookie) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		params := make([]*network.CookieParam, 0, len(cookies))
+		for _, c := range cookies {
+			param := &network.CookieParam{Name: c.Name, Value: c.Value, Domain: c.Domain, Path: c.Path}
+			if param.Domain == "" {
+				param.URL = pageURL
+			}
+			params = append(params, param)
 		}
-	case "iPhone 15 Pro":
-		err := chromedp.Run(ctx,
-			// todo 这里直接使用 15pro 图像不对，暂时用 12pro
-			chromedp.Emulate(device.IPhone12Pro),
-			chromedp.Navigate(url),
-			chromedp.Sleep(3*time.Second),
-			chromedp.WaitVisible("body", chromedp.ByQuery),
-			chromedp.CaptureScreenshot(&buf),
-		)
-		if err != nil {
-			return nil, err
+		return network.SetCookies(params).Do(ctx)
+	})
+}
+
+// setHeadersAction 设置导航时附带的额外 HTTP 请求头
+func setHeadersAction(headers map[string]string) chromedp.Action {
+	h := make(network.Headers, len(headers))
+	for k, v := range headers {
+		h[k] = v
+	}
+	return network.SetExtraHTTPHeaders(h)
+}
+
+// basicAuthAction 开启 fetch 域的请求拦截：收到认证质询时用配置的凭据自动应答，
+// 其余请求原样放行，实现导航前的 HTTP 基础认证
+func basicAuthAction(auth *devicecatalog.BasicAuth) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			switch e := ev.(type) {
+			case *fetch.EventAuthRequired:
+				go func() {
+					execCtx := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Target)
+					_ = fetch.ContinueWithAuth(e.RequestID, &fetch.AuthChallengeResponse{
+						Response: fetch.AuthChallengeResponseResponseProvideCredentials,
+						Username: auth.Username,
+						Password: auth.Password,
+					}).Do(execCtx)
+				}()
+			case *fetch.EventRequestPaused:
+				go func() {
+					execCtx := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Target)
+					_ = fetch.ContinueRequest(e.RequestID).Do(execCtx)
+				}()
+			}
+		})
+
+		return fetch.Enable().WithHandleAuthRequests(true).Do(ctx)
+	})
+}
+
+// waitForNetworkIdle 开启网络事件监听，每当有请求完成就重置计时器，直到 idleTimeout
+// 这段时间内再没有新的请求完成为止；用于替代固定延时等待 SPA 异步加载完成
+func waitForNetworkIdle(idleTimeout time.Duration) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := network.Enable().Do(ctx); err != nil {
+			return err
+		}
+
+		timer := time.NewTimer(idleTimeout)
+		defer timer.Stop()
+
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			if _, ok := ev.(*network.EventLoadingFinished); ok {
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(idleTimeout)
+			}
+		})
+
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// waitAction 根据设备配置的等待策略构建就绪检测动作：selector 等待指定元素可见，
+// idle 等待网络空闲一段时间，delay（默认，含零值）固定等待 DelayMs（未设置时 3 秒）
+func waitAction(w devicecatalog.Wait) chromedp.Action {
+	switch w.Strategy {
+	case devicecatalog.WaitSelector:
+		return chromedp.WaitVisible(w.Selector, chromedp.ByQuery)
+	case devicecatalog.WaitIdle:
+		idleTimeout := time.Duration(w.IdleTimeoutMs) * time.Millisecond
+		if idleTimeout <= 0 {
+			idleTimeout = 500 * time.Millisecond
 		}
+		return waitForNetworkIdle(idleTimeout)
+	default:
+		delay := time.Duration(w.DelayMs) * time.Millisecond
+		if delay <= 0 {
+			delay = 3 * time.Second
+		}
+		return chromedp.Sleep(delay)
+	}
+}
+
+// takeScreenshotForDevice 按设备的模拟参数截图，并在配置了圆角半径时应用圆角透明效果。
+// fullPage 为 true 时先滚动触发懒加载内容，再撑满视口高度截取整个可滚动页面。
+// colorScheme 非空时（dark/light）会在导航前强制页面按该配色方案渲染。
+func takeScreenshotForDevice(ctx context.Context, url string, dev DeviceParams, fullPage bool, colorScheme string) (*image.RGBA, error) {
+	var buf []byte
+
+	emulate, emulated, err := emulateAction(dev.Emulation, dev.ScreenW, dev.ScreenH)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := []chromedp.Action{emulate}
+	if colorScheme != "" {
+		actions = append(actions, colorSchemeMediaAction(colorScheme))
+	}
+	if dev.BasicAuth != nil {
+		actions = append(actions, basicAuthAction(dev.BasicAuth))
+	}
+	if len(dev.Headers) > 0 {
+		actions = append(actions, setHeadersAction(dev.Headers))
+	}
+	if len(dev.Cookies) > 0 {
+		actions = append(actions, setCookiesAction(url, dev.Cookies))
+	}
+	actions = append(actions,
+		chromedp.Navigate(url),
+		waitAction(dev.Wait),
+		chromedp.WaitVisible("body", chromedp.ByQuery),
+	)
+	if dev.PostLoadScript != "" {
+		actions = append(actions, chromedp.ActionFunc(func(ctx context.Context) error {
+			var discard interface{}
+			return chromedp.Evaluate(dev.PostLoadScript, &discard).Do(ctx)
+		}))
+	}
+
+	if fullPage {
+		actions = append(actions,
+			expandToFullPage(emulated),
+			chromedp.ActionFunc(func(ctx context.Context) error {
+				data, err := page.CaptureScreenshot().WithCaptureBeyondViewport(true).Do(ctx)
+				if err != nil {
+					return err
+				}
+				buf = data
+				return nil
+			}),
+		)
+	} else {
+		actions = append(actions, chromedp.CaptureScreenshot(&buf))
+	}
+
+	if err := chromedp.Run(ctx, actions...); err != nil {
+		return nil, err
 	}
 
 	img, _, err := image.Decode(bytes.NewReader(buf))
@@ -243,68 +530,157 @@ func takeScreenshotForDevice(ctx context.Context, url string, width, height int,
 	rgba := image.NewRGBA(bounds)
 	draw.Draw(rgba, bounds, img, bounds.Min, draw.Src)
 
-	// 如果是 iPhone 15 Pro，应用圆角效果
-	if deviceName == "iPhone 15 Pro" {
-		rgba = applyCornerTransparency(rgba, 120.0) // 120.0 是圆角半径
+	if dev.CornerRadius > 0 {
+		rgba = applyCornerTransparency(rgba, dev.CornerRadius)
 	}
 
 	return rgba, nil
 }
 
-// applyCornerTransparency 圆角透明
+// expandToFullPage 先从顶部到底部按视口高度分步滚动页面，给懒加载图片和
+// IntersectionObserver 驱动的内容留出触发时间，再滚回顶部，并把布局视口的高度设置为
+// document.body.scrollHeight，使后续截图能覆盖整个可滚动页面。
+// 宽度取当前页面实际的 document.documentElement.clientWidth，而不是设备外壳的屏幕
+// 区域像素宽度（两者对于走 preset 模拟的机型并不相等，例如 iPad Pro 的外壳区域是
+// 624px 但模拟出的 CSS 视口是 1024px，用错会导致整页截图按错误的窄视口重新排版）；
+// emulated 则用来在重设视口高度时保留 chromedp.Emulate 设置的 DPR/mobile/touch，
+// 避免 EmulateViewport 把它们重置为默认值。
+func expandToFullPage(emulated device.Info) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var viewportWidth int64
+		if err := chromedp.Evaluate(`document.documentElement.clientWidth`, &viewportWidth).Do(ctx); err != nil {
+			return err
+		}
+
+		var viewportHeight int64
+		if err := chromedp.Evaluate(`window.innerHeight`, &viewportHeight).Do(ctx); err != nil {
+			return err
+		}
+		if viewportHeight == 0 {
+			viewportHeight = 800
+		}
+
+		for step := 0; step < fullPageMaxScrollSteps; step++ {
+			var atBottom bool
+			script := fmt.Sprintf(`(() => {
+				window.scrollBy(0, %d);
+				return window.scrollY + window.innerHeight >= document.body.scrollHeight;
+			})()`, viewportHeight)
+			if err := chromedp.Evaluate(script, &atBottom).Do(ctx); err != nil {
+				return err
+			}
+			if err := chromedp.Sleep(fullPageScrollStep).Do(ctx); err != nil {
+				return err
+			}
+			if atBottom {
+				break
+			}
+		}
+
+		var discard interface{}
+		if err := chromedp.Evaluate(`window.scrollTo(0, 0)`, &discard).Do(ctx); err != nil {
+			return err
+		}
+		if err := chromedp.Sleep(fullPageScrollStep).Do(ctx); err != nil {
+			return err
+		}
+
+		var scrollHeight int64
+		if err := chromedp.Evaluate(`document.body.scrollHeight`, &scrollHeight).Do(ctx); err != nil {
+			return err
+		}
+
+		scale := emulated.Scale
+		if scale <= 0 {
+			scale = 1
+		}
+		if err := emulation.SetDeviceMetricsOverride(viewportWidth, scrollHeight, scale, emulated.Mobile).Do(ctx); err != nil {
+			return err
+		}
+		return emulation.SetTouchEmulationEnabled(emulated.Touch).Do(ctx)
+	})
+}
+
+// cornerAASamples 是圆角抗锯齿时每个像素在每个轴上的子像素采样数（共 cornerAASamples^2 个采样点）
+const cornerAASamples = 4
+
+// applyCornerTransparency 将图像四角裁成圆角。直接按像素中心阈值判断会产生锯齿，
+// 这里对靠近圆角的像素用 cornerAASamples x cornerAASamples 的子像素网格采样，
+// 按落在圆角曲线内部的采样点比例设置 alpha（0-255），实现抗锯齿边缘。
+// image.RGBA 是预乘 alpha 格式，过渡带像素必须把 R/G/B 和 A 一起按 coverage
+// 缩放，否则 RGB 会大于缩小后的 A，形成一个无效的预乘像素——用 draw.Over 合成时
+// 圆角边缘会出现一圈发亮的色边。
 func applyCornerTransparency(src *image.RGBA, cornerRadius float64) *image.RGBA {
 	bounds := src.Bounds()
 	width := bounds.Dx()
 	height := bounds.Dy()
-	r := cornerRadius
 
-	// 直接操作原图的像素数据
 	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
 		for x := bounds.Min.X; x < bounds.Max.X; x++ {
-			// 判断当前像素是否在某个需要变为透明的圆角内
-			if isInCorner(x, y, width, height, r) {
-				// 计算该像素在像素数组中的索引位置
-				idx := (y-bounds.Min.Y)*src.Stride + (x-bounds.Min.X)*4
-				// 将RGBA中的A (Alpha) 通道设置为0 (完全透明)
-				src.Pix[idx] = 0   // R
-				src.Pix[idx+1] = 0 // G
-				src.Pix[idx+2] = 0 // B
-				src.Pix[idx+3] = 0 // A
+			lx, ly := x-bounds.Min.X, y-bounds.Min.Y
+			if !nearCorner(lx, ly, width, height, cornerRadius) {
+				continue
+			}
+
+			coverage := cornerCoverage(lx, ly, width, height, cornerRadius)
+			if coverage >= 1 {
+				continue
 			}
+
+			idx := ly*src.Stride + lx*4
+			src.Pix[idx] = uint8(float64(src.Pix[idx]) * coverage)
+			src.Pix[idx+1] = uint8(float64(src.Pix[idx+1]) * coverage)
+			src.Pix[idx+2] = uint8(float64(src.Pix[idx+2]) * coverage)
+			src.Pix[idx+3] = uint8(float64(src.Pix[idx+3]) * coverage)
 		}
 	}
 
 	return src
 }
 
-// isInCorner 判断点(x, y)是否位于四个圆角之一的区域内（应被透明化）
-func isInCorner(x, y, width, height int, radius float64) bool {
-	// 将当前坐标转换为相对于四个角圆心的坐标
-	// 左上角圆心: (radius, radius)
-	if x < int(radius) && y < int(radius) {
-		dx := float64(x) - radius
-		dy := float64(y) - radius
-		return dx*dx+dy*dy > radius*radius
+// nearCorner 判断像素是否落在需要做圆角裁剪/抗锯齿采样的四角区域内（粗略包围盒判断，
+// 用于跳过绝大多数无需采样的像素）
+func nearCorner(x, y, width, height int, radius float64) bool {
+	r := int(radius) + 1
+	return (x < r && y < r) || (x > width-r && y < r) ||
+		(x < r && y > height-r) || (x > width-r && y > height-r)
+}
+
+// cornerCoverage 返回像素 (x, y) 落在圆角裁剪区域内的覆盖率：0 表示完全在圆角外
+// （应全透明），1 表示完全在圆角内（保持不透明），中间值用于过渡带的抗锯齿
+func cornerCoverage(x, y, width, height int, radius float64) float64 {
+	var inside int
+	for sy := 0; sy < cornerAASamples; sy++ {
+		for sx := 0; sx < cornerAASamples; sx++ {
+			px := float64(x) + (float64(sx)+0.5)/cornerAASamples
+			py := float64(y) + (float64(sy)+0.5)/cornerAASamples
+			if !isOutsideCorner(px, py, width, height, radius) {
+				inside++
+			}
+		}
 	}
-	// 右上角圆心: (float64(width)-radius, radius)
-	if x > width-int(radius)-1 && y < int(radius) {
-		dx := float64(x) - (float64(width) - radius)
-		dy := float64(y) - radius
+
+	return float64(inside) / float64(cornerAASamples*cornerAASamples)
+}
+
+// isOutsideCorner 判断连续坐标点 (x, y) 是否落在四个圆角之一的圆弧之外（应被透明化）
+func isOutsideCorner(x, y float64, width, height int, radius float64) bool {
+	w, h := float64(width), float64(height)
+
+	switch {
+	case x < radius && y < radius:
+		dx, dy := x-radius, y-radius
 		return dx*dx+dy*dy > radius*radius
-	}
-	// 左下角圆心: (radius, float64(height)-radius)
-	if x < int(radius) && y > height-int(radius)-1 {
-		dx := float64(x) - radius
-		dy := float64(y) - (float64(height) - radius)
+	case x > w-radius && y < radius:
+		dx, dy := x-(w-radius), y-radius
 		return dx*dx+dy*dy > radius*radius
-	}
-	// 右下角圆心: (float64(width)-radius, float64(height)-radius)
-	if x > width-int(radius)-1 && y > height-int(radius)-1 {
-		dx := float64(x) - (float64(width) - radius)
-		dy := float64(y) - (float64(height) - radius)
+	case x < radius && y > h-radius:
+		dx, dy := x-radius, y-(h-radius)
 		return dx*dx+dy*dy > radius*radius
+	case x > w-radius && y > h-radius:
+		dx, dy := x-(w-radius), y-(h-radius)
+		return dx*dx+dy*dy > radius*radius
+	default:
+		return false
 	}
-
-	// 不在任何一个角的处理区域内
-	return false
 }
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// WorkerPool 管理固定数量的常驻 worker，串联一个任务队列消费。
+// serve 模式下所有请求共用同一个 chromedp.ExecAllocator；每个任务的标签页上下文
+// 直接派生自该分配器，首次 Run 时会启动一个属于该任务的浏览器进程，任务结束后随之关闭。
+// 同时存在的浏览器进程数因此以 worker 数量为上限，但浏览器进程不会在任务之间复用。
+type WorkerPool struct {
+	allocCtx context.Context
+	cancel   context.CancelFunc
+	jobs     chan *Job
+	wg       sync.WaitGroup
+	timeout  time.Duration
+}
+
+// NewWorkerPool 创建一个 worker 池，concurrency 为常驻 worker 数量（即同时处理的任务数），
+// timeout 为单个任务在标签页上执行的最长时间（超过后该标签页会被强制取消），
+// 避免某个任务的等待策略/选择器/导航一直挂起而永久占用一个 worker 名额
+func NewWorkerPool(browserPath string, concurrency int, timeout time.Duration) (*WorkerPool, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.ExecPath(browserPath),
+		chromedp.NoFirstRun,
+		chromedp.NoDefaultBrowserCheck,
+		chromedp.Headless,
+	)
+	allocCtx, cancel := chromedp.NewExecAllocator(context.Background(), opts...)
+
+	p := &WorkerPool{
+		allocCtx: allocCtx,
+		cancel:   cancel,
+		jobs:     make(chan *Job, concurrency*4),
+		timeout:  timeout,
+	}
+
+	for i := 0; i < concurrency; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+
+	return p, nil
+}
+
+// worker 串行处理队列中的任务，每个任务各自新建一个标签页并在完成后关闭。
+// 标签页上下文都派生自同一个 allocCtx，各自启动自己的浏览器进程（同时存在的进程数
+// 不超过 worker 数量），不会跨任务复用：
+// setHeadersAction/setCookiesAction/basicAuthAction/waitForNetworkIdle 等动作
+// 会在标签页上留下 header、cookie、fetch 拦截、事件监听等全局状态，若复用同一个
+// 标签页，这些状态会污染到下一个任务（甚至造成跨请求的凭据泄露）。
+func (p *WorkerPool) worker() {
+	defer p.wg.Done()
+
+	for job := range p.jobs {
+		p.runJob(job)
+	}
+}
+
+// runJob 为单个任务新建一个标签页上下文，处理完成后立即关闭，避免状态和
+// ListenTarget 监听跨任务残留；标签页上下文额外套一层 p.timeout 超时，
+// 防止任务本身的等待策略/选择器/导航挂起时无限占用这个 worker
+func (p *WorkerPool) runJob(job *Job) {
+	tabCtx, tabCancel := chromedp.NewContext(p.allocCtx)
+	defer tabCancel()
+
+	timeoutCtx, timeoutCancel := context.WithTimeout(tabCtx, p.timeout)
+	defer timeoutCancel()
+
+	job.run(timeoutCtx)
+}
+
+// Submit 将任务放入队列，由空闲 worker 领取处理
+func (p *WorkerPool) Submit(job *Job) {
+	p.jobs <- job
+}
+
+// Ping 从分配器新建一个临时标签页上下文（会启动一个临时浏览器进程）并导航到空白页，
+// 检查浏览器能否正常启动，用于健康检查
+func (p *WorkerPool) Ping(ctx context.Context) error {
+	tabCtx, tabCancel := chromedp.NewContext(p.allocCtx)
+	defer tabCancel()
+
+	pingCtx, pingCancel := context.WithTimeout(tabCtx, 5*time.Second)
+	defer pingCancel()
+
+	return chromedp.Run(pingCtx, chromedp.Navigate("about:blank"))
+}
+
+// Close 停止接收新任务，等待在途任务完成，并释放浏览器分配器
+func (p *WorkerPool) Close() {
+	close(p.jobs)
+	p.wg.Wait()
+	p.cancel()
+}
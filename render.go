@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+
+	"github.com/vespeng/multi-device-preview/compositor"
+)
+
+// buildDeviceShots 读取并解码每个设备的外壳图片，与其截图一起打包成
+// compositor.DeviceShot，供具体的 Layout 排布合成
+func buildDeviceShots(devices []DeviceParams, shots map[string]*image.RGBA) ([]compositor.DeviceShot, error) {
+	out := make([]compositor.DeviceShot, 0, len(devices))
+
+	for _, dev := range devices {
+		screenshot, ok := shots[dev.Name]
+		if !ok {
+			continue
+		}
+
+		data, err := readDeviceImage(dev.DevicePath)
+		if err != nil {
+			return nil, fmt.Errorf("❌ 读取设备图片失败 (%s): %w", dev.DevicePath, err)
+		}
+
+		shellImg, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("❌ 解码设备图片失败 (%s): %w", dev.DevicePath, err)
+		}
+
+		out = append(out, compositor.DeviceShot{
+			Device:     dev,
+			Screenshot: screenshot,
+			Shell:      shellImg,
+		})
+	}
+
+	return out, nil
+}
+
+// readDeviceImage 读取设备外壳图片：优先从内置 embed.FS 中查找（对应随可执行文件
+// 打包的内置设备），找不到时回退到可执行文件同级目录下的磁盘路径，使 devices.yaml
+// 里通过 device_path 引用的外部外壳图片（如 devices.example.yaml 的 Pixel 8）无需
+// 重新编译即可生效。
+func readDeviceImage(devicePath string) ([]byte, error) {
+	if data, err := deviceFiles.ReadFile(devicePath); err == nil {
+		return data, nil
+	}
+
+	execPath, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(filepath.Join(filepath.Dir(execPath), devicePath))
+}
+
+// renderCanvas 用给定的布局把各设备截图贴入外壳并合成为一块画布
+func renderCanvas(devices []DeviceParams, shots map[string]*image.RGBA, layout compositor.Layout, opts compositor.Options) (*image.RGBA, error) {
+	deviceShots, err := buildDeviceShots(devices, shots)
+	if err != nil {
+		return nil, err
+	}
+
+	return layout.Compose(deviceShots, opts)
+}
+
+// renderPDFPages 为每个设备各生成一页（使用 PresetSingleDevice 布局），用于 PDF 多页输出
+func renderPDFPages(devices []DeviceParams, shots map[string]*image.RGBA, opts compositor.Options) ([]compositor.NamedImage, error) {
+	deviceShots, err := buildDeviceShots(devices, shots)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make([]compositor.NamedImage, 0, len(deviceShots))
+	for _, shot := range deviceShots {
+		canvas, err := compositor.PresetSingleDevice.Compose([]compositor.DeviceShot{shot}, opts)
+		if err != nil {
+			return nil, fmt.Errorf("❌ 生成 PDF 页面失败 (%s): %w", shot.Device.Name, err)
+		}
+		pages = append(pages, compositor.NamedImage{Name: shot.Device.Name, Image: canvas})
+	}
+
+	return pages, nil
+}
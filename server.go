@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ServeOptions 描述 HTTP 微服务模式的启动参数
+type ServeOptions struct {
+	Addr           string        // HTTP 监听地址
+	Concurrency    int           // 常驻 worker（并发处理任务）数量
+	RequestTimeout time.Duration // 同步请求等待生成结果的超时时间
+}
+
+// runServer 启动 REST API，由 WorkerPool 中固定数量的 worker 异步处理截图任务
+func runServer(browserPath string, opts ServeOptions) error {
+	pool, err := NewWorkerPool(browserPath, opts.Concurrency, opts.RequestTimeout)
+	if err != nil {
+		return fmt.Errorf("❌ 初始化浏览器池失败: %w", err)
+	}
+	defer pool.Close()
+
+	store := newJobStore()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", handleHealthz(pool))
+	mux.HandleFunc("/preview", handlePreview(pool, store, opts.RequestTimeout))
+	mux.HandleFunc("/preview/", handlePreviewStatus(store))
+
+	fmt.Println("🚀 HTTP 服务已启动:", opts.Addr)
+	return http.ListenAndServe(opts.Addr, mux)
+}
+
+// handleHealthz 通过 pool.Ping 临时启动一个浏览器并导航到空白页，确认浏览器可用
+func handleHealthz(pool *WorkerPool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := pool.Ping(ctx); err != nil {
+			http.Error(w, "浏览器不可用: "+err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	}
+}
+
+// handlePreview 处理 POST /preview：提交任务后，默认同步等待结果，Async=true 时立即返回任务 id
+func handlePreview(pool *WorkerPool, store *jobStore, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req PreviewRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "请求体解析失败: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.URL == "" {
+			http.Error(w, "url 不能为空", http.StatusBadRequest)
+			return
+		}
+
+		job := newJob(req)
+		store.put(job)
+		pool.Submit(job)
+
+		if req.Async {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			_ = json.NewEncoder(w).Encode(map[string]string{"id": job.ID})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		if err := job.Wait(ctx); err != nil {
+			http.Error(w, "生成超时: "+err.Error(), http.StatusGatewayTimeout)
+			return
+		}
+		writeJobResult(w, job.Snapshot())
+	}
+}
+
+// handlePreviewStatus 处理 GET /preview/:id：任务未完成时返回状态，完成后返回渲染结果
+func handlePreviewStatus(store *jobStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/preview/")
+		job, ok := store.get(id)
+		if !ok {
+			http.Error(w, "任务不存在", http.StatusNotFound)
+			return
+		}
+
+		snapshot := job.Snapshot()
+		switch snapshot.Status {
+		case JobPending, JobRunning:
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]string{"status": string(snapshot.Status)})
+		default:
+			writeJobResult(w, snapshot)
+		}
+	}
+}
+
+// writeJobResult 将任务结果写入响应：成功则按 ContentType 返回渲染结果，失败则返回错误信息
+func writeJobResult(w http.ResponseWriter, snapshot JobSnapshot) {
+	if snapshot.Status == JobFailed {
+		http.Error(w, "生成失败: "+snapshot.Err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", snapshot.ContentType)
+	_, _ = w.Write(snapshot.Result)
+}